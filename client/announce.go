@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+
+	"github.com/xytis/arp"
+)
+
+// Timing parameters for address probing and announcement, as defined in
+// RFC 5227.
+const (
+	probeWait        = 1 * time.Second
+	probeNum         = 3
+	probeMin         = 1 * time.Second
+	probeMax         = 2 * time.Second
+	announceWait     = 2 * time.Second
+	announceNum      = 2
+	announceInterval = 2 * time.Second
+)
+
+// Probe checks whether ip is already in use on the network, as described in
+// RFC 5227 section 2.1.1. It broadcasts probeNum ARP requests with a
+// zero sender IP address, with a uniformly random delay between probeMin
+// and probeMax between each, listening for replies in between and for
+// probeMax after the last, and returns an *AddressConflictError if any
+// host answers for ip.
+func (c *Client) Probe(ip net.IP) error {
+	time.Sleep(probeWait)
+
+	for i := 0; i < probeNum; i++ {
+		if err := c.send(arp.OperationRequest, c.hwAddr, net.IPv4zero, ethernet.Broadcast, ip); err != nil {
+			return err
+		}
+
+		wait := probeMax
+		if i < probeNum-1 {
+			wait = probeMin + time.Duration(rand.Int63n(int64(probeMax-probeMin)))
+		}
+		deadline := time.Now().Add(wait)
+
+		hw, err := c.readReplyUntil(context.Background(), ip, deadline)
+		if err == nil {
+			return &AddressConflictError{IP: ip, HardwareAddr: hw}
+		}
+		if err != ErrTimeout {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Announce broadcasts announceNum ARP Announcements for ip, as described in
+// RFC 5227 section 2.3, so that other hosts on the network update any
+// stale cache entries for ip.
+func (c *Client) Announce(ip net.IP) error {
+	time.Sleep(announceWait)
+
+	for i := 0; i < announceNum; i++ {
+		if err := c.Gratuitous(ip); err != nil {
+			return err
+		}
+
+		if i < announceNum-1 {
+			time.Sleep(announceInterval)
+		}
+	}
+
+	return nil
+}
+
+// Gratuitous broadcasts a single gratuitous ARP packet for ip, with both
+// sender and target protocol addresses set to ip, prompting other hosts to
+// update any cached mapping for ip to the Client's hardware address.
+func (c *Client) Gratuitous(ip net.IP) error {
+	return c.send(arp.OperationRequest, c.hwAddr, ip, ethernet.Broadcast, ip)
+}
@@ -0,0 +1,223 @@
+// Package client provides an ARP client built on raw AF_PACKET sockets,
+// for resolving IPv4 addresses to hardware addresses and responding to
+// incoming ARP requests.
+package client
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/raw"
+
+	"github.com/xytis/arp"
+)
+
+// Default parameters governing Resolve's retransmission behavior.
+const (
+	initialTimeout = 1 * time.Second
+	maxRetries     = 3
+)
+
+// resolvePollInterval bounds how long readReplyUntil's blocking read can
+// run past ctx cancellation.
+const resolvePollInterval = 250 * time.Millisecond
+
+// A Client is an ARP client, used to resolve IPv4 addresses to hardware
+// addresses, and to announce or respond to ARP traffic on a network
+// interface.
+type Client struct {
+	ifi    *net.Interface
+	conn   *raw.Conn
+	hwAddr net.HardwareAddr
+	ip     net.IP
+}
+
+// NewClient creates a new Client using the IPv4 address and ARP hardware
+// address associated with ifi.
+func NewClient(ifi *net.Interface) (*Client, error) {
+	conn, err := raw.ListenPacket(ifi, uint16(arp.EtherTypeARP), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, err := firstIPv4Addr(ifi)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Client{
+		ifi:    ifi,
+		conn:   conn,
+		hwAddr: ifi.HardwareAddr,
+		ip:     ip,
+	}, nil
+}
+
+// Close closes the Client's underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// SetDeadline sets the read and write deadlines associated with the
+// Client's underlying connection, as with net.Conn's SetDeadline.
+func (c *Client) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline associated with the Client's
+// underlying connection, as with net.Conn's SetReadDeadline.
+func (c *Client) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline associated with the Client's
+// underlying connection, as with net.Conn's SetWriteDeadline.
+func (c *Client) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// Request broadcasts an ARP request for ip on the Client's interface,
+// without waiting for a reply.
+func (c *Client) Request(ip net.IP) error {
+	return c.send(arp.OperationRequest, c.hwAddr, c.ip, ethernet.Broadcast, ip)
+}
+
+// Reply sends an ARP reply to req, advertising hw as the hardware address
+// for req's target IP.
+func (c *Client) Reply(req *arp.Packet, hw net.HardwareAddr) error {
+	return c.send(arp.OperationReply, hw, req.TargetIP, req.SenderHardwareAddr, req.SenderIP)
+}
+
+// Resolve sends an ARP request for ip and blocks until a matching reply is
+// received or its retransmissions are exhausted. Requests are
+// retransmitted with exponential backoff starting at one second, up to
+// maxRetries times. If no reply arrives in time, ErrTimeout is returned.
+//
+// Resolve sets its own read deadlines on the underlying connection, so a
+// read deadline set with SetDeadline or SetReadDeadline does not limit how
+// long it waits for a reply.
+func (c *Client) Resolve(ip net.IP) (net.HardwareAddr, error) {
+	return c.ResolveContext(context.Background(), ip)
+}
+
+// ResolveContext is like Resolve, but additionally aborts the operation if
+// ctx is done before a reply arrives: ErrTimeout is returned if ctx's
+// deadline has passed, and ctx.Err() if ctx was canceled.
+func (c *Client) ResolveContext(ctx context.Context, ip net.IP) (net.HardwareAddr, error) {
+	timeout := initialTimeout
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			if err == context.DeadlineExceeded {
+				return nil, ErrTimeout
+			}
+			return nil, err
+		}
+
+		if err := c.Request(ip); err != nil {
+			return nil, err
+		}
+
+		deadline := time.Now().Add(timeout)
+
+		hw, err := c.readReplyUntil(ctx, ip, deadline)
+		if err == nil {
+			return hw, nil
+		}
+		if err == context.DeadlineExceeded {
+			return nil, ErrTimeout
+		}
+		if err != ErrTimeout {
+			return nil, err
+		}
+
+		timeout *= 2
+	}
+
+	return nil, ErrTimeout
+}
+
+// readReplyUntil reads incoming frames until it finds an ARP reply whose
+// SenderIP matches ip, the deadline passes, or ctx is canceled. The
+// underlying read is bounded by resolvePollInterval so that ctx
+// cancellation is noticed promptly, rather than only between retries.
+func (c *Client) readReplyUntil(ctx context.Context, ip net.IP, deadline time.Time) (net.HardwareAddr, error) {
+	buf := make([]byte, c.ifi.MTU)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		if !now.Before(deadline) {
+			return nil, ErrTimeout
+		}
+
+		readDeadline := now.Add(resolvePollInterval)
+		if readDeadline.After(deadline) {
+			readDeadline = deadline
+		}
+		if err := c.conn.SetReadDeadline(readDeadline); err != nil {
+			return nil, err
+		}
+
+		n, _, err := c.conn.ReadFrom(buf)
+		if err != nil {
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+				continue
+			}
+			return nil, err
+		}
+
+		pkt, _, err := arp.ParseFrame(buf[:n])
+		if err != nil {
+			// Malformed or non-ARP frame; ignore and keep reading.
+			continue
+		}
+
+		if pkt.Operation == arp.OperationReply && pkt.SenderIP.Equal(ip) {
+			return pkt.SenderHardwareAddr, nil
+		}
+	}
+}
+
+// send marshals an ARP packet with the given fields and transmits it in an
+// Ethernet frame addressed to dstHW.
+func (c *Client) send(op arp.Operation, srcHW net.HardwareAddr, srcIP net.IP, dstHW net.HardwareAddr, dstIP net.IP) error {
+	pkt, err := arp.NewPacket(op, srcHW, srcIP, dstHW, dstIP)
+	if err != nil {
+		return err
+	}
+
+	frame, err := pkt.MarshalFrame(dstHW)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.conn.WriteTo(frame, &raw.Addr{HardwareAddr: dstHW})
+	return err
+}
+
+// firstIPv4Addr returns the first IPv4 address assigned to ifi.
+func firstIPv4Addr(ifi *net.Interface) (net.IP, error) {
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if v4 := ipNet.IP.To4(); v4 != nil {
+			return v4, nil
+		}
+	}
+
+	return nil, &net.AddrError{Err: "no IPv4 address assigned to interface", Addr: ifi.Name}
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"fmt"
+	"net"
+)
+
+// ErrTimeout is returned by Client.Resolve and Client.ResolveContext when no
+// matching ARP reply is received before all retransmissions have timed out
+// or the context's deadline passes.
+var ErrTimeout = fmt.Errorf("arp: resolution timed out")
+
+// An AddressConflictError is returned by Client.Probe when another host on
+// the network answers for an IP address the caller intends to claim, as
+// described in RFC 5227.
+type AddressConflictError struct {
+	// IP is the address being probed.
+	IP net.IP
+
+	// HardwareAddr is the hardware address of the host that answered for
+	// IP.
+	HardwareAddr net.HardwareAddr
+}
+
+func (e *AddressConflictError) Error() string {
+	return fmt.Sprintf("arp: address conflict for %s: already in use by %s", e.IP, e.HardwareAddr)
+}
@@ -0,0 +1,86 @@
+package arp
+
+import (
+	"errors"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// ErrInvalidEtherType is returned when a parsed Ethernet frame does not
+// carry the ARP EtherType (0x0806), either directly or inside an 802.1Q
+// VLAN tag.
+var ErrInvalidEtherType = errors.New("invalid EtherType for ARP frame")
+
+// EtherTypeARP is the EtherType used to identify an Ethernet frame as
+// carrying an ARP payload.
+const EtherTypeARP = ethernet.EtherType(0x0806)
+
+// A Frame is an ARP Packet wrapped in an Ethernet II frame, with an
+// optional 802.1Q VLAN tag.
+//
+// Frame is a thin convenience wrapper around mdlayher/ethernet.Frame; it
+// exists so callers don't have to hand-roll the EtherType and VLAN tag
+// bookkeeping that every ARP transmitter otherwise duplicates.
+type Frame struct {
+	// Destination and Source are the Ethernet hardware addresses of the
+	// frame.
+	Destination net.HardwareAddr
+	Source      net.HardwareAddr
+
+	// VLAN is the 802.1Q VLAN tag present on the frame, or nil if the
+	// frame is untagged.
+	VLAN *ethernet.VLAN
+
+	// Packet is the decoded ARP payload carried by the frame.
+	Packet *Packet
+}
+
+// MarshalFrame marshals p into a complete Ethernet II frame addressed to
+// dstHW, using p.SenderHardwareAddr as the frame's source address.
+func (p *Packet) MarshalFrame(dstHW net.HardwareAddr) ([]byte, error) {
+	payload, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &ethernet.Frame{
+		Destination: dstHW,
+		Source:      p.SenderHardwareAddr,
+		EtherType:   EtherTypeARP,
+		Payload:     payload,
+	}
+
+	return f.MarshalBinary()
+}
+
+// ParseFrame parses b as an Ethernet II frame (optionally 802.1Q tagged)
+// carrying an ARP packet, returning both the decoded Packet and the
+// surrounding Frame metadata.
+//
+// ParseFrame returns ErrInvalidEtherType if the frame's EtherType does not
+// indicate ARP.
+func ParseFrame(b []byte) (*Packet, *Frame, error) {
+	ef := new(ethernet.Frame)
+	if err := ef.UnmarshalBinary(b); err != nil {
+		return nil, nil, err
+	}
+
+	if ef.EtherType != EtherTypeARP {
+		return nil, nil, ErrInvalidEtherType
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(ef.Payload); err != nil {
+		return nil, nil, err
+	}
+
+	f := &Frame{
+		Destination: ef.Destination,
+		Source:      ef.Source,
+		VLAN:        ef.VLAN,
+		Packet:      p,
+	}
+
+	return p, f, nil
+}
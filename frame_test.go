@@ -0,0 +1,91 @@
+package arp
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+// arpRequestFrame is a hand-verified Ethernet II frame carrying an ARP
+// request, equivalent to what a packet capture of `arping 192.168.1.2`
+// issued from 192.168.1.1 would contain.
+var arpRequestFrame = []byte{
+	// Ethernet header
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // destination: broadcast
+	0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff, // source
+	0x08, 0x06, // EtherType: ARP
+
+	// ARP payload
+	0x00, 0x01, // hardware type: Ethernet
+	0x08, 0x00, // protocol type: IPv4
+	0x06,       // hardware address length
+	0x04,       // protocol address length
+	0x00, 0x01, // operation: request
+	0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff, // sender hardware address
+	0xc0, 0xa8, 0x01, 0x01, // sender protocol address: 192.168.1.1
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // target hardware address
+	0xc0, 0xa8, 0x01, 0x02, // target protocol address: 192.168.1.2
+}
+
+func TestParseFrame(t *testing.T) {
+	pkt, f, err := ParseFrame(arpRequestFrame)
+	if err != nil {
+		t.Fatalf("ParseFrame: %v", err)
+	}
+
+	want := net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if !bytes.Equal(f.Destination, want) {
+		t.Errorf("Destination = %v, want %v", f.Destination, want)
+	}
+
+	want = net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	if !bytes.Equal(f.Source, want) {
+		t.Errorf("Source = %v, want %v", f.Source, want)
+	}
+
+	if pkt.Operation != OperationRequest {
+		t.Errorf("Operation = %v, want %v", pkt.Operation, OperationRequest)
+	}
+	if want := net.IPv4(192, 168, 1, 1).To4(); !pkt.SenderIP.Equal(want) {
+		t.Errorf("SenderIP = %v, want %v", pkt.SenderIP, want)
+	}
+	if want := net.IPv4(192, 168, 1, 2).To4(); !pkt.TargetIP.Equal(want) {
+		t.Errorf("TargetIP = %v, want %v", pkt.TargetIP, want)
+	}
+}
+
+func TestParseFrameInvalidEtherType(t *testing.T) {
+	b := make([]byte, len(arpRequestFrame))
+	copy(b, arpRequestFrame)
+
+	// Overwrite the EtherType field with IPv4's.
+	b[12], b[13] = 0x08, 0x00
+
+	if _, _, err := ParseFrame(b); err != ErrInvalidEtherType {
+		t.Fatalf("ParseFrame err = %v, want %v", err, ErrInvalidEtherType)
+	}
+}
+
+func TestMarshalFrameRoundTrip(t *testing.T) {
+	pkt, _, err := ParseFrame(arpRequestFrame)
+	if err != nil {
+		t.Fatalf("ParseFrame: %v", err)
+	}
+
+	b, err := pkt.MarshalFrame(net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	if err != nil {
+		t.Fatalf("MarshalFrame: %v", err)
+	}
+
+	got, f, err := ParseFrame(b)
+	if err != nil {
+		t.Fatalf("ParseFrame(round trip): %v", err)
+	}
+
+	if !bytes.Equal(f.Source, pkt.SenderHardwareAddr) {
+		t.Errorf("round-tripped Source = %v, want %v", f.Source, pkt.SenderHardwareAddr)
+	}
+	if got.Operation != pkt.Operation || !got.SenderIP.Equal(pkt.SenderIP) || !got.TargetIP.Equal(pkt.TargetIP) {
+		t.Errorf("round-tripped packet = %+v, want %+v", got, pkt)
+	}
+}
@@ -0,0 +1,89 @@
+package arp
+
+import (
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// LayerTypeARP is the gopacket.LayerType used to register this package's
+// Packet as a decodable ARP layer.
+var LayerTypeARP = gopacket.RegisterLayerType(
+	1000,
+	gopacket.LayerTypeMetadata{
+		Name:    "ARP",
+		Decoder: gopacket.DecodeFunc(decodeARP),
+	},
+)
+
+func init() {
+	// Replace gopacket/layers' built-in ARP decoder with this package's,
+	// so EtherType 0x0806 decodes directly into an *arp.Packet.
+	layers.EthernetTypeMetadata[layers.EthernetTypeARP] = layers.EnumMetadata{
+		DecodeWith: gopacket.DecodeFunc(decodeARP),
+		Name:       "ARP",
+		LayerType:  LayerTypeARP,
+	}
+}
+
+func decodeARP(data []byte, p gopacket.PacketBuilder) error {
+	pkt := new(Packet)
+	if err := pkt.DecodeFromBytes(data, p); err != nil {
+		return err
+	}
+	p.AddLayer(pkt)
+	return p.NextDecoder(pkt.NextLayerType())
+}
+
+// LayerType returns LayerTypeARP.
+func (p *Packet) LayerType() gopacket.LayerType { return LayerTypeARP }
+
+// LayerContents returns the bytes of the ARP packet, marshaled from p's
+// current fields.
+func (p *Packet) LayerContents() []byte {
+	b, _ := p.MarshalBinary()
+	return b
+}
+
+// LayerPayload returns nil, as an ARP packet never carries a payload of
+// its own.
+func (p *Packet) LayerPayload() []byte { return nil }
+
+// CanDecode returns the set of layer types this Packet can decode, which
+// is just LayerTypeARP.
+func (p *Packet) CanDecode() gopacket.LayerClass { return LayerTypeARP }
+
+// NextLayerType returns gopacket.LayerTypeZero, since ARP is always the
+// last layer in a packet.
+func (p *Packet) NextLayerType() gopacket.LayerType { return gopacket.LayerTypeZero }
+
+// DecodeFromBytes decodes the supplied bytes into p, satisfying
+// gopacket.DecodingLayer. Decode errors are returned; df is not used.
+func (p *Packet) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	if err := p.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SerializeTo writes the serialized form of p into b, satisfying
+// gopacket.SerializableLayer. If opts.FixLengths is set, the
+// HardwareAddrLength and IPLength fields are recomputed from the address
+// fields before serialization.
+func (p *Packet) SerializeTo(b gopacket.SerializeBuffer, opts gopacket.SerializeOptions) error {
+	if opts.FixLengths {
+		p.HardwareAddrLength = uint8(len(p.SenderHardwareAddr))
+		p.IPLength = uint8(len(p.SenderIP))
+	}
+
+	raw, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	buf, err := b.PrependBytes(len(raw))
+	if err != nil {
+		return err
+	}
+	copy(buf, raw)
+	return nil
+}
@@ -0,0 +1,54 @@
+package arp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestGopacketDecode(t *testing.T) {
+	p := gopacket.NewPacket(arpRequestFrame, layers.LayerTypeEthernet, gopacket.Default)
+	if err := p.ErrorLayer(); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	l := p.Layer(LayerTypeARP)
+	if l == nil {
+		t.Fatal("no ARP layer decoded")
+	}
+
+	pkt, ok := l.(*Packet)
+	if !ok {
+		t.Fatalf("layer has type %T, want *Packet", l)
+	}
+
+	if pkt.Operation != OperationRequest {
+		t.Errorf("Operation = %v, want %v", pkt.Operation, OperationRequest)
+	}
+	if want := net.IPv4(192, 168, 1, 1).To4(); !pkt.SenderIP.Equal(want) {
+		t.Errorf("SenderIP = %v, want %v", pkt.SenderIP, want)
+	}
+}
+
+func TestPacketSerializeTo(t *testing.T) {
+	pkt, _, err := ParseFrame(arpRequestFrame)
+	if err != nil {
+		t.Fatalf("ParseFrame: %v", err)
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	if err := pkt.SerializeTo(buf, gopacket.SerializeOptions{FixLengths: true}); err != nil {
+		t.Fatalf("SerializeTo: %v", err)
+	}
+
+	var got Packet
+	if err := got.UnmarshalBinary(buf.Bytes()); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	if got.Operation != pkt.Operation || !got.SenderIP.Equal(pkt.SenderIP) {
+		t.Errorf("serialized packet = %+v, want %+v", got, pkt)
+	}
+}
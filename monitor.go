@@ -0,0 +1,343 @@
+package arp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/raw"
+)
+
+// DefaultAgeOut is the default duration a Binding is retained after its
+// last refresh, used when a MonitorConfig does not specify AgeOut.
+const DefaultAgeOut = 5 * time.Minute
+
+// An EventType describes the kind of change a Monitor observed in its
+// binding table.
+type EventType uint8
+
+// EventType constants describing the binding changes a Monitor can emit.
+const (
+	// EventNew indicates a previously unseen IP address was observed.
+	EventNew EventType = iota
+
+	// EventRefresh indicates an IP address was observed again, mapping to
+	// the same hardware address as before.
+	EventRefresh
+
+	// EventMoved indicates an IP address now maps to a different hardware
+	// address than it did previously, which may signal ARP spoofing or
+	// DHCP churn.
+	EventMoved
+
+	// EventGratuitous indicates a gratuitous ARP packet was observed,
+	// where the sender and target IP addresses are equal.
+	EventGratuitous
+)
+
+// String returns the name of an EventType.
+func (t EventType) String() string {
+	switch t {
+	case EventNew:
+		return "new"
+	case EventRefresh:
+		return "refresh"
+	case EventMoved:
+		return "moved"
+	case EventGratuitous:
+		return "gratuitous"
+	default:
+		return "unknown"
+	}
+}
+
+// An Event describes a single change observed in a Monitor's binding table.
+type Event struct {
+	// Type is the kind of change that occurred.
+	Type EventType
+
+	// IP is the sender IP address of the ARP packet that produced this
+	// Event.
+	IP net.IP
+
+	// HardwareAddr is the sender hardware address of the ARP packet that
+	// produced this Event.
+	HardwareAddr net.HardwareAddr
+
+	// Previous is the hardware address that was previously bound to IP.
+	// It is only populated for EventMoved.
+	Previous net.HardwareAddr
+
+	// Time is the time at which the Event was observed.
+	Time time.Time
+}
+
+// A Binding is an observed association between an IP address and a
+// hardware address, along with the time it was last refreshed.
+type Binding struct {
+	IP           net.IP
+	HardwareAddr net.HardwareAddr
+	LastSeen     time.Time
+}
+
+// An Observer receives Events as they are produced by a Monitor, in
+// addition to the Monitor's Events channel. Implementations should return
+// quickly, as Observe is called synchronously from the Monitor's read
+// loop.
+type Observer interface {
+	Observe(Event)
+}
+
+// The ObserverFunc type is an adapter to allow the use of ordinary
+// functions as Observers.
+type ObserverFunc func(Event)
+
+// Observe calls f(e).
+func (f ObserverFunc) Observe(e Event) { f(e) }
+
+// A MonitorConfig configures optional parameters for a Monitor. A nil
+// MonitorConfig is treated as the zero value, with AgeOut defaulting to
+// DefaultAgeOut.
+type MonitorConfig struct {
+	// AgeOut specifies how long a Binding is retained after its last
+	// refresh before it is evicted from the table. If zero, DefaultAgeOut
+	// is used.
+	AgeOut time.Duration
+
+	// Observer, if non-nil, receives every Event produced by the Monitor,
+	// synchronously with delivery to the Events channel.
+	Observer Observer
+}
+
+// A Monitor passively decodes ARP frames read from an io.Reader, maintains
+// a table of observed IP-to-hardware-address bindings, and reports
+// changes to that table as Events.
+type Monitor struct {
+	r         io.Reader
+	closer    io.Closer
+	deadlined readDeadliner
+	ageOut    time.Duration
+	observer  Observer
+
+	events chan Event
+
+	mu      sync.Mutex
+	table   map[string]Binding
+	closing chan struct{}
+	closed  chan struct{}
+}
+
+// NewMonitor creates a Monitor which decodes ARP frames read from r. cfg
+// may be nil to use default settings.
+func NewMonitor(r io.Reader, cfg *MonitorConfig) *Monitor {
+	if cfg == nil {
+		cfg = &MonitorConfig{}
+	}
+
+	ageOut := cfg.AgeOut
+	if ageOut <= 0 {
+		ageOut = DefaultAgeOut
+	}
+
+	return &Monitor{
+		r:        r,
+		ageOut:   ageOut,
+		observer: cfg.Observer,
+		events:   make(chan Event, 64),
+		table:    make(map[string]Binding),
+		closing:  make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+// NewMonitorOnInterface creates a Monitor which listens promiscuously for
+// ARP traffic on the named network interface.
+func NewMonitorOnInterface(name string, cfg *MonitorConfig) (*Monitor, error) {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := raw.ListenPacket(ifi, uint16(EtherTypeARP), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetPromiscuous(true); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	m := NewMonitor(&packetConnReader{conn: conn}, cfg)
+	m.closer = conn
+	m.deadlined = conn
+	return m, nil
+}
+
+// readDeadliner is implemented by sources that support bounding a Read
+// call with a deadline, such as *raw.Conn.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// runPollInterval bounds how long Run's read loop can block past ctx
+// cancellation when the Monitor's source supports read deadlines.
+const runPollInterval = 1 * time.Second
+
+// packetConnReader adapts a net.PacketConn to an io.Reader, returning one
+// frame per Read call.
+type packetConnReader struct {
+	conn net.PacketConn
+}
+
+func (r *packetConnReader) Read(b []byte) (int, error) {
+	n, _, err := r.conn.ReadFrom(b)
+	return n, err
+}
+
+// Run reads and decodes frames from the Monitor's source until ctx is
+// canceled, the source returns io.EOF, or an unrecoverable read error
+// occurs. Run also drives the Monitor's AgeOut eviction and must be
+// running for Snapshot to reflect expirations; eviction emits no Event.
+func (m *Monitor) Run(ctx context.Context) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	go m.ageLoop(ctx, done)
+
+	buf := make([]byte, 65535)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if m.deadlined != nil {
+			if err := m.deadlined.SetReadDeadline(time.Now().Add(runPollInterval)); err != nil {
+				return err
+			}
+		}
+
+		n, err := m.r.Read(buf)
+		if err != nil {
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+				continue
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		pkt, _, err := ParseFrame(buf[:n])
+		if err != nil {
+			// Malformed or non-ARP frame; ignore and keep reading.
+			continue
+		}
+
+		m.observe(pkt)
+	}
+}
+
+// observe updates the binding table for pkt and emits any resulting Event.
+func (m *Monitor) observe(pkt *Packet) {
+	now := time.Now()
+	key := pkt.SenderIP.String()
+
+	m.mu.Lock()
+	prev, ok := m.table[key]
+	m.table[key] = Binding{
+		IP:           pkt.SenderIP,
+		HardwareAddr: pkt.SenderHardwareAddr,
+		LastSeen:     now,
+	}
+	m.mu.Unlock()
+
+	ev := Event{
+		IP:           pkt.SenderIP,
+		HardwareAddr: pkt.SenderHardwareAddr,
+		Time:         now,
+	}
+
+	switch {
+	case pkt.SenderIP.Equal(pkt.TargetIP):
+		ev.Type = EventGratuitous
+	case !ok:
+		ev.Type = EventNew
+	case !bytes.Equal(prev.HardwareAddr, pkt.SenderHardwareAddr):
+		ev.Type = EventMoved
+		ev.Previous = prev.HardwareAddr
+	default:
+		ev.Type = EventRefresh
+	}
+
+	m.emit(ev)
+}
+
+// emit delivers ev to the Observer, if any, and to the Events channel,
+// dropping it if the channel is full so a slow consumer cannot stall the
+// read loop.
+func (m *Monitor) emit(ev Event) {
+	if m.observer != nil {
+		m.observer.Observe(ev)
+	}
+
+	select {
+	case m.events <- ev:
+	default:
+	}
+}
+
+// ageLoop evicts Bindings that have not been refreshed within the
+// Monitor's AgeOut duration.
+func (m *Monitor) ageLoop(ctx context.Context, done <-chan struct{}) {
+	ticker := time.NewTicker(m.ageOut / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case now := <-ticker.C:
+			m.mu.Lock()
+			for key, b := range m.table {
+				if now.Sub(b.LastSeen) > m.ageOut {
+					delete(m.table, key)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+// Events returns the channel on which the Monitor delivers Events.
+func (m *Monitor) Events() <-chan Event {
+	return m.events
+}
+
+// Snapshot returns a point-in-time copy of the Monitor's binding table.
+func (m *Monitor) Snapshot() []Binding {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bindings := make([]Binding, 0, len(m.table))
+	for _, b := range m.table {
+		bindings = append(bindings, b)
+	}
+	return bindings
+}
+
+// Close closes the Monitor's underlying connection, if it was created by
+// NewMonitorOnInterface.
+func (m *Monitor) Close() error {
+	if m.closer == nil {
+		return nil
+	}
+	return m.closer.Close()
+}
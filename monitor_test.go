@@ -0,0 +1,87 @@
+package arp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+)
+
+// fakeFrameReader is an io.Reader that returns one pre-built Ethernet frame
+// per Read call, then io.EOF once exhausted.
+type fakeFrameReader struct {
+	frames [][]byte
+	i      int
+}
+
+func (r *fakeFrameReader) Read(b []byte) (int, error) {
+	if r.i >= len(r.frames) {
+		return 0, io.EOF
+	}
+	n := copy(b, r.frames[r.i])
+	r.i++
+	return n, nil
+}
+
+func mustFrame(t *testing.T, op Operation, srcHW net.HardwareAddr, srcIP net.IP, dstIP net.IP) []byte {
+	t.Helper()
+
+	p, err := NewPacket(op, srcHW, srcIP, net.HardwareAddr{0, 0, 0, 0, 0, 0}, dstIP)
+	if err != nil {
+		t.Fatalf("NewPacket: %v", err)
+	}
+
+	b, err := p.MarshalFrame(net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	if err != nil {
+		t.Fatalf("MarshalFrame: %v", err)
+	}
+	return b
+}
+
+func TestMonitorEventSequence(t *testing.T) {
+	hw1 := net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	hw2 := net.HardwareAddr{0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb}
+	ip1 := net.IPv4(192, 168, 1, 10)
+
+	frames := [][]byte{
+		mustFrame(t, OperationRequest, hw1, ip1, net.IPv4(192, 168, 1, 1)), // EventNew
+		mustFrame(t, OperationRequest, hw1, ip1, net.IPv4(192, 168, 1, 2)), // EventRefresh
+		mustFrame(t, OperationRequest, hw2, ip1, net.IPv4(192, 168, 1, 3)), // EventMoved
+		mustFrame(t, OperationRequest, hw2, ip1, ip1),                      // EventGratuitous
+	}
+
+	m := NewMonitor(&fakeFrameReader{frames: frames}, nil)
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []EventType{EventNew, EventRefresh, EventMoved, EventGratuitous}
+	for i, wantType := range want {
+		select {
+		case ev := <-m.Events():
+			if ev.Type != wantType {
+				t.Fatalf("event %d: Type = %v, want %v", i, ev.Type, wantType)
+			}
+			if !ev.IP.Equal(ip1) {
+				t.Fatalf("event %d: IP = %v, want %v", i, ev.IP, ip1)
+			}
+		default:
+			t.Fatalf("event %d: no event available, want %v", i, wantType)
+		}
+	}
+
+	select {
+	case ev := <-m.Events():
+		t.Fatalf("unexpected extra event: %+v", ev)
+	default:
+	}
+
+	bindings := m.Snapshot()
+	if len(bindings) != 1 {
+		t.Fatalf("Snapshot returned %d bindings, want 1", len(bindings))
+	}
+	if !bytes.Equal(bindings[0].HardwareAddr, hw2) {
+		t.Errorf("final binding HardwareAddr = %v, want %v", bindings[0].HardwareAddr, hw2)
+	}
+}
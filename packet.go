@@ -26,13 +26,25 @@ type Operation uint16
 const (
 	OperationRequest Operation = 1
 	OperationReply   Operation = 2
+
+	// OperationRARPRequest and OperationRARPReply are used by RARP, the
+	// Reverse Address Resolution Protocol described in RFC 903, to map a
+	// hardware address to a protocol address.
+	OperationRARPRequest Operation = 3
+	OperationRARPReply   Operation = 4
+
+	// OperationInARPRequest and OperationInARPReply are used by InARP, the
+	// Inverse Address Resolution Protocol described in RFC 2390, typically
+	// over non-broadcast media such as Frame Relay and ATM.
+	OperationInARPRequest Operation = 8
+	OperationInARPReply   Operation = 9
 )
 
 // A Packet is a raw ARP packet, as described in RFC 826.
 type Packet struct {
 	// HardwareType specifies an IANA-assigned hardware type, as described
 	// in RFC 826.
-	HardwareType uint16
+	HardwareType HardwareType
 
 	// ProtocolType specifies the internetwork protocol for which the ARP
 	// request is intended.  Typically, this is the IPv4 EtherType.
@@ -68,12 +80,44 @@ type Packet struct {
 // NewPacket creates a new Packet from an input Operation and hardware/IPv4
 // address values for both a sender and target.
 //
+// NewPacket assumes a HardwareEthernet hardware type. To build a Packet for
+// another hardware type, or one carrying non-IPv4 protocol addresses, use
+// NewPacketWithType instead.
+//
 // If either hardware address is less than 6 bytes in length, or there is a
 // length mismatch between the two, ErrInvalidHardwareAddr is returned.
 //
 // If either IP address is not an IPv4 address, or there is a length mismatch
 // between the two, ErrInvalidIP is returned.
 func NewPacket(op Operation, srcHW net.HardwareAddr, srcIP net.IP, dstHW net.HardwareAddr, dstIP net.IP) (*Packet, error) {
+	p, err := NewPacketWithType(HardwareEthernet, op, srcHW, srcIP, dstHW, dstIP)
+	if err != nil {
+		return nil, err
+	}
+	if p.IPLength != net.IPv4len {
+		return nil, ErrInvalidIP
+	}
+	return p, nil
+}
+
+// NewPacketWithType creates a new Packet from an input HardwareType,
+// Operation, and hardware/protocol address values for both a sender and
+// target.
+//
+// Unlike NewPacket, NewPacketWithType does not require IPv4 protocol
+// addresses. If both srcIP and dstIP are IPv4 addresses (in 4-byte or
+// 16-byte form), they are stored in their 4-byte form and ProtocolType is
+// set to the EtherType for IPv4. Addresses of
+// any other matching length are also accepted, to support protocols such as
+// InARP (which may carry a zero-length target protocol address) or
+// non-IPv4 protocol addresses such as IPX; in that case, callers are
+// responsible for setting the resulting Packet's ProtocolType.
+//
+// If either hardware address is less than 6 bytes in length, or there is a
+// length mismatch between the two, ErrInvalidHardwareAddr is returned.
+//
+// If srcIP and dstIP do not have the same length, ErrInvalidIP is returned.
+func NewPacketWithType(hw HardwareType, op Operation, srcHW net.HardwareAddr, srcIP net.IP, dstHW net.HardwareAddr, dstIP net.IP) (*Packet, error) {
 	// Validate hardware addresses for minimum length, and matching length
 	if len(srcHW) < 6 {
 		return nil, ErrInvalidHardwareAddr
@@ -85,24 +129,27 @@ func NewPacket(op Operation, srcHW net.HardwareAddr, srcIP net.IP, dstHW net.Har
 		return nil, ErrInvalidHardwareAddr
 	}
 
-	// Validate IP addresses to ensure they are IPv4 addresses, and
-	// correct length
-	srcIP = srcIP.To4()
-	if srcIP == nil {
-		return nil, ErrInvalidIP
+	// Prefer the 4-byte IPv4 representation when available, but otherwise
+	// allow any protocol address length, so long as sender and target
+	// agree; this permits non-IPv4 protocol addresses (for example,
+	// 6-byte IPX) as well as InARP's zero-length target address.
+	protoType := uint16(0)
+	if v4 := srcIP.To4(); v4 != nil {
+		srcIP = v4
+	}
+	if v4 := dstIP.To4(); v4 != nil {
+		dstIP = v4
 	}
-	dstIP = dstIP.To4()
-	if dstIP == nil {
+	if len(srcIP) != len(dstIP) {
 		return nil, ErrInvalidIP
 	}
+	if len(srcIP) == net.IPv4len {
+		protoType = uint16(ethernet.EtherTypeIPv4)
+	}
 
 	return &Packet{
-		// There is no Go-native way to detect hardware type of a network
-		// interface, so default to 1 (ethernet 10Mb) for now
-		HardwareType: 1,
-
-		// Default to EtherType for IPv4
-		ProtocolType: uint16(ethernet.EtherTypeIPv4),
+		HardwareType: hw,
+		ProtocolType: protoType,
 
 		// Populate other fields using input data
 		HardwareAddrLength: uint8(len(srcHW)),
@@ -125,14 +172,14 @@ func (p *Packet) MarshalBinary() ([]byte, error) {
 	// 1 byte : protocol length
 	// 2 bytes: operation
 	// N bytes: source hardware address
-	// 4 bytes: source protocol address
+	// M bytes: source protocol address
 	// N bytes: target hardware address
-	// 4 bytes: target protocol address
-	b := make([]byte, 2+2+1+1+2+4+4+(p.HardwareAddrLength*2))
+	// M bytes: target protocol address
+	b := make([]byte, 2+2+1+1+2+int(p.HardwareAddrLength)*2+int(p.IPLength)*2)
 
 	// Marshal fixed length data
 
-	binary.BigEndian.PutUint16(b[0:2], p.HardwareType)
+	binary.BigEndian.PutUint16(b[0:2], uint16(p.HardwareType))
 	binary.BigEndian.PutUint16(b[2:4], p.ProtocolType)
 
 	b[4] = p.HardwareAddrLength
@@ -170,7 +217,7 @@ func (p *Packet) UnmarshalBinary(b []byte) error {
 
 	// Retrieve fixed length data
 
-	p.HardwareType = binary.BigEndian.Uint16(b[0:2])
+	p.HardwareType = HardwareType(binary.BigEndian.Uint16(b[0:2]))
 	p.ProtocolType = binary.BigEndian.Uint16(b[2:4])
 
 	p.HardwareAddrLength = b[4]
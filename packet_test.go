@@ -0,0 +1,135 @@
+package arp
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestNewPacketWithTypeTable(t *testing.T) {
+	hw1 := net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	hw2 := net.HardwareAddr{0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb}
+
+	tests := []struct {
+		name     string
+		hw       HardwareType
+		op       Operation
+		srcIP    net.IP
+		dstIP    net.IP
+		wantIPLn uint8
+	}{
+		{
+			name:     "Ethernet/IPv4 request",
+			hw:       HardwareEthernet,
+			op:       OperationRequest,
+			srcIP:    net.IPv4(192, 168, 1, 1),
+			dstIP:    net.IPv4(192, 168, 1, 2),
+			wantIPLn: net.IPv4len,
+		},
+		{
+			name:     "Ethernet RARP request",
+			hw:       HardwareEthernet,
+			op:       OperationRARPRequest,
+			srcIP:    net.IPv4(0, 0, 0, 0),
+			dstIP:    net.IPv4(10, 0, 0, 5),
+			wantIPLn: net.IPv4len,
+		},
+		{
+			name:     "Ethernet RARP reply",
+			hw:       HardwareEthernet,
+			op:       OperationRARPReply,
+			srcIP:    net.IPv4(10, 0, 0, 5),
+			dstIP:    net.IPv4(0, 0, 0, 0),
+			wantIPLn: net.IPv4len,
+		},
+		{
+			name:     "Frame Relay InARP request with zero-length target",
+			hw:       HardwareFrameRelay,
+			op:       OperationInARPRequest,
+			srcIP:    net.IP{},
+			dstIP:    net.IP{},
+			wantIPLn: 0,
+		},
+		{
+			name:     "Ethernet InARP reply",
+			hw:       HardwareEthernet,
+			op:       OperationInARPReply,
+			srcIP:    net.IPv4(172, 16, 0, 1),
+			dstIP:    net.IPv4(172, 16, 0, 2),
+			wantIPLn: net.IPv4len,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPacketWithType(tt.hw, tt.op, hw1, tt.srcIP, hw2, tt.dstIP)
+			if err != nil {
+				t.Fatalf("NewPacketWithType: %v", err)
+			}
+
+			if p.HardwareType != tt.hw {
+				t.Errorf("HardwareType = %v, want %v", p.HardwareType, tt.hw)
+			}
+			if p.IPLength != tt.wantIPLn {
+				t.Errorf("IPLength = %d, want %d", p.IPLength, tt.wantIPLn)
+			}
+
+			b, err := p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+
+			wantLen := 8 + 2*int(p.HardwareAddrLength) + 2*int(p.IPLength)
+			if len(b) != wantLen {
+				t.Fatalf("MarshalBinary produced %d bytes, want %d", len(b), wantLen)
+			}
+
+			var got Packet
+			if err := got.UnmarshalBinary(b); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+
+			if got.HardwareType != p.HardwareType {
+				t.Errorf("round-tripped HardwareType = %v, want %v", got.HardwareType, p.HardwareType)
+			}
+			if got.Operation != p.Operation {
+				t.Errorf("round-tripped Operation = %v, want %v", got.Operation, p.Operation)
+			}
+			if !bytes.Equal(got.SenderHardwareAddr, p.SenderHardwareAddr) {
+				t.Errorf("round-tripped SenderHardwareAddr = %v, want %v", got.SenderHardwareAddr, p.SenderHardwareAddr)
+			}
+			if !got.SenderIP.Equal(p.SenderIP) && !(len(got.SenderIP) == 0 && len(p.SenderIP) == 0) {
+				t.Errorf("round-tripped SenderIP = %v, want %v", got.SenderIP, p.SenderIP)
+			}
+		})
+	}
+}
+
+func TestHardwareTypeString(t *testing.T) {
+	if got := HardwareEthernet.String(); got != "Ethernet" {
+		t.Errorf("HardwareEthernet.String() = %q, want %q", got, "Ethernet")
+	}
+	if got := HardwareType(0xfff).String(); got != "4095" {
+		t.Errorf("unknown HardwareType.String() = %q, want %q", got, "4095")
+	}
+}
+
+func TestOperationString(t *testing.T) {
+	tests := []struct {
+		op   Operation
+		want string
+	}{
+		{OperationRequest, "request"},
+		{OperationReply, "reply"},
+		{OperationRARPRequest, "RARP request"},
+		{OperationRARPReply, "RARP reply"},
+		{OperationInARPRequest, "InARP request"},
+		{OperationInARPReply, "InARP reply"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("Operation(%d).String() = %q, want %q", tt.op, got, tt.want)
+		}
+	}
+}
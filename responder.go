@@ -0,0 +1,234 @@
+package arp
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mdlayher/raw"
+)
+
+// DefaultRateLimit is the minimum interval between replies sent to the same
+// source hardware address, used when a ResponderConfig does not specify
+// RateLimit.
+const DefaultRateLimit = 1 * time.Second
+
+// A Handler decides how a Responder should answer an ARP request. It
+// returns the hardware address to advertise for req.TargetIP, and whether
+// the Responder should reply at all.
+type Handler func(req *Packet) (hw net.HardwareAddr, ok bool)
+
+// StaticTable returns a Handler that answers requests for the IPv4
+// addresses present in table, keyed by their string form (as returned by
+// net.IP.String).
+func StaticTable(table map[string]net.HardwareAddr) Handler {
+	return func(req *Packet) (net.HardwareAddr, bool) {
+		hw, ok := table[req.TargetIP.String()]
+		return hw, ok
+	}
+}
+
+// SubnetProxy returns a Handler that answers with hw for any request whose
+// target IP falls within one of nets, implementing classic proxy-ARP.
+func SubnetProxy(nets []*net.IPNet, hw net.HardwareAddr) Handler {
+	return func(req *Packet) (net.HardwareAddr, bool) {
+		for _, n := range nets {
+			if n.Contains(req.TargetIP) {
+				return hw, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// Stats holds counters describing a Responder's activity.
+type Stats struct {
+	// RequestsSeen counts well-formed ARP requests read by the Responder.
+	RequestsSeen uint64
+
+	// RepliesSent counts replies transmitted by the Responder.
+	RepliesSent uint64
+
+	// Dropped counts requests the Responder discarded, whether for being
+	// malformed or for exceeding the per-source rate limit.
+	Dropped uint64
+}
+
+// A ResponderConfig configures optional parameters for a Responder. A nil
+// ResponderConfig is treated as the zero value, with RateLimit defaulting
+// to DefaultRateLimit.
+type ResponderConfig struct {
+	// AnswerOwnIP allows the Responder to reply to requests targeting one
+	// of the local interface's own IP addresses. It is false by default,
+	// since answering for the interface's own address usually indicates a
+	// misconfigured Handler rather than intentional proxy behavior.
+	AnswerOwnIP bool
+
+	// RateLimit is the minimum interval between replies sent to the same
+	// source hardware address, used to defeat ARP flood amplification. If
+	// zero, DefaultRateLimit is used.
+	RateLimit time.Duration
+}
+
+// A Responder answers incoming ARP requests on behalf of other hosts,
+// consulting a Handler to decide how each request should be answered.
+type Responder struct {
+	conn net.PacketConn
+	ifi  *net.Interface
+	h    Handler
+
+	answerOwnIP bool
+	rateLimit   time.Duration
+	ownIPs      map[string]bool
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+
+	stats Stats
+}
+
+// NewResponder creates a Responder that reads ARP requests from conn,
+// using ifi to determine the local interface's own IPv4 addresses, and
+// consulting h to produce replies. cfg may be nil to use default settings.
+func NewResponder(conn net.PacketConn, ifi *net.Interface, h Handler, cfg *ResponderConfig) (*Responder, error) {
+	if cfg == nil {
+		cfg = &ResponderConfig{}
+	}
+
+	rateLimit := cfg.RateLimit
+	if rateLimit <= 0 {
+		rateLimit = DefaultRateLimit
+	}
+
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	ownIPs := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if v4 := ipNet.IP.To4(); v4 != nil {
+			ownIPs[v4.String()] = true
+		}
+	}
+
+	return &Responder{
+		conn:        conn,
+		ifi:         ifi,
+		h:           h,
+		answerOwnIP: cfg.AnswerOwnIP,
+		rateLimit:   rateLimit,
+		ownIPs:      ownIPs,
+		lastSeen:    make(map[string]time.Time),
+	}, nil
+}
+
+// servePollInterval bounds how long Serve's read loop can block past ctx
+// cancellation.
+const servePollInterval = 1 * time.Second
+
+// Serve reads and answers ARP requests until ctx is canceled, the
+// connection returns io.EOF, an unrecoverable read error occurs, or a
+// reply cannot be built or written.
+func (r *Responder) Serve(ctx context.Context) error {
+	buf := make([]byte, 65535)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if err := r.conn.SetReadDeadline(time.Now().Add(servePollInterval)); err != nil {
+			return err
+		}
+
+		n, _, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		pkt, _, err := ParseFrame(buf[:n])
+		if err != nil {
+			atomic.AddUint64(&r.stats.Dropped, 1)
+			continue
+		}
+
+		if pkt.Operation != OperationRequest {
+			continue
+		}
+		atomic.AddUint64(&r.stats.RequestsSeen, 1)
+
+		if !r.answerOwnIP && r.ownIPs[pkt.TargetIP.String()] {
+			continue
+		}
+
+		if r.limited(pkt.SenderHardwareAddr) {
+			atomic.AddUint64(&r.stats.Dropped, 1)
+			continue
+		}
+
+		hw, ok := r.h(pkt)
+		if !ok {
+			continue
+		}
+
+		if err := r.reply(pkt, hw); err != nil {
+			return err
+		}
+		atomic.AddUint64(&r.stats.RepliesSent, 1)
+	}
+}
+
+// limited reports whether a reply to src would exceed the Responder's
+// configured rate limit. When it would not, the current time is recorded
+// as src's latest attempt; a limited attempt is not recorded.
+func (r *Responder) limited(src net.HardwareAddr) bool {
+	key := src.String()
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if last, ok := r.lastSeen[key]; ok && now.Sub(last) < r.rateLimit {
+		return true
+	}
+	r.lastSeen[key] = now
+	return false
+}
+
+// reply sends an ARP reply advertising hw as the hardware address for
+// req.TargetIP.
+func (r *Responder) reply(req *Packet, hw net.HardwareAddr) error {
+	pkt, err := NewPacket(OperationReply, hw, req.TargetIP, req.SenderHardwareAddr, req.SenderIP)
+	if err != nil {
+		return err
+	}
+
+	frame, err := pkt.MarshalFrame(req.SenderHardwareAddr)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn.WriteTo(frame, &raw.Addr{HardwareAddr: req.SenderHardwareAddr})
+	return err
+}
+
+// Stats returns a snapshot of the Responder's activity counters.
+func (r *Responder) Stats() Stats {
+	return Stats{
+		RequestsSeen: atomic.LoadUint64(&r.stats.RequestsSeen),
+		RepliesSent:  atomic.LoadUint64(&r.stats.RepliesSent),
+		Dropped:      atomic.LoadUint64(&r.stats.Dropped),
+	}
+}
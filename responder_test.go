@@ -0,0 +1,150 @@
+package arp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// pipePacketConn adapts a net.Conn, such as one half of a net.Pipe, to the
+// net.PacketConn interface expected by Responder.
+type pipePacketConn struct {
+	net.Conn
+}
+
+func (c *pipePacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, err := c.Read(b)
+	return n, c.RemoteAddr(), err
+}
+
+func (c *pipePacketConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	return c.Write(b)
+}
+
+func TestResponderStaticTable(t *testing.T) {
+	testResponder(t, StaticTable(map[string]net.HardwareAddr{
+		"10.0.0.2": {0xde, 0xad, 0xbe, 0xef, 0x00, 0x01},
+	}), net.IPv4(10, 0, 0, 2), net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01})
+}
+
+func TestResponderSubnetProxy(t *testing.T) {
+	_, cidr, err := net.ParseCIDR("10.0.0.0/24")
+	if err != nil {
+		t.Fatalf("ParseCIDR: %v", err)
+	}
+
+	hw := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x02}
+	testResponder(t, SubnetProxy([]*net.IPNet{cidr}, hw), net.IPv4(10, 0, 0, 42), hw)
+}
+
+func testResponder(t *testing.T, h Handler, target net.IP, wantHW net.HardwareAddr) {
+	t.Helper()
+
+	ifi, err := net.InterfaceByName("lo")
+	if err != nil {
+		t.Skipf("no loopback interface available: %v", err)
+	}
+
+	connA, connB := net.Pipe()
+	defer connA.Close()
+	defer connB.Close()
+
+	r, err := NewResponder(&pipePacketConn{Conn: connA}, ifi, h, nil)
+	if err != nil {
+		t.Fatalf("NewResponder: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- r.Serve(context.Background()) }()
+
+	clientHW := net.HardwareAddr{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+	req, err := NewPacket(OperationRequest, clientHW, net.IPv4(10, 0, 0, 1), net.HardwareAddr{0, 0, 0, 0, 0, 0}, target)
+	if err != nil {
+		t.Fatalf("NewPacket: %v", err)
+	}
+
+	frame, err := req.MarshalFrame(net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	if err != nil {
+		t.Fatalf("MarshalFrame: %v", err)
+	}
+
+	connB.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := connB.Write(frame); err != nil {
+		t.Fatalf("write request: %v", err)
+	}
+
+	buf := make([]byte, 1500)
+	n, err := connB.Read(buf)
+	if err != nil {
+		t.Fatalf("read reply: %v", err)
+	}
+
+	reply, _, err := ParseFrame(buf[:n])
+	if err != nil {
+		t.Fatalf("ParseFrame(reply): %v", err)
+	}
+
+	if reply.Operation != OperationReply {
+		t.Errorf("Operation = %v, want %v", reply.Operation, OperationReply)
+	}
+	if !reply.SenderIP.Equal(target) {
+		t.Errorf("SenderIP = %v, want %v", reply.SenderIP, target)
+	}
+	if string(reply.SenderHardwareAddr) != string(wantHW) {
+		t.Errorf("SenderHardwareAddr = %v, want %v", reply.SenderHardwareAddr, wantHW)
+	}
+
+	// The Responder increments RepliesSent immediately after its write
+	// unblocks the pipe read above, but that happens on its own
+	// goroutine; poll briefly rather than racing it.
+	var stats Stats
+	for i := 0; i < 100; i++ {
+		stats = r.Stats()
+		if stats.RequestsSeen == 1 && stats.RepliesSent == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if stats.RequestsSeen != 1 || stats.RepliesSent != 1 {
+		t.Errorf("Stats = %+v, want RequestsSeen=1 RepliesSent=1", stats)
+	}
+
+	connA.Close()
+	connB.Close()
+	<-done
+}
+
+// TestResponderServeCancel verifies that Serve returns promptly after ctx
+// is canceled, even on a connection with no incoming traffic.
+func TestResponderServeCancel(t *testing.T) {
+	ifi, err := net.InterfaceByName("lo")
+	if err != nil {
+		t.Skipf("no loopback interface available: %v", err)
+	}
+
+	connA, connB := net.Pipe()
+	defer connA.Close()
+	defer connB.Close()
+
+	r, err := NewResponder(&pipePacketConn{Conn: connA}, ifi, StaticTable(nil), nil)
+	if err != nil {
+		t.Fatalf("NewResponder: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Serve(ctx) }()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("Serve returned %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(2 * servePollInterval):
+		t.Fatal("Serve did not return within 2x servePollInterval of ctx cancellation")
+	}
+}
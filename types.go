@@ -0,0 +1,84 @@
+package arp
+
+import "strconv"
+
+// A HardwareType is an IANA-assigned hardware type, as described in RFC 826
+// and the IANA ARP Parameters registry.
+type HardwareType uint16
+
+// HardwareType constants for the hardware types most commonly seen in the
+// wild. This is not an exhaustive list of the IANA registry.
+const (
+	HardwareEthernet             HardwareType = 1
+	HardwareExperimentalEthernet HardwareType = 2
+	HardwareAX25                 HardwareType = 3
+	HardwareProNET               HardwareType = 4
+	HardwareChaos                HardwareType = 5
+	HardwareIEEE802              HardwareType = 6
+	HardwareARCNET               HardwareType = 7
+	HardwareFrameRelay           HardwareType = 15
+	HardwareATM                  HardwareType = 16
+	HardwareHDLC                 HardwareType = 17
+	HardwareFibreChannel         HardwareType = 18
+	HardwareSerialLine           HardwareType = 20
+	HardwareFireWire             HardwareType = 24
+	HardwareInfiniBand           HardwareType = 32
+)
+
+// String returns the name of an IANA-assigned hardware type, or its
+// numeric value if the type is not recognized.
+func (h HardwareType) String() string {
+	switch h {
+	case HardwareEthernet:
+		return "Ethernet"
+	case HardwareExperimentalEthernet:
+		return "Experimental Ethernet"
+	case HardwareAX25:
+		return "Amateur Radio AX.25"
+	case HardwareProNET:
+		return "ProNET Token Ring"
+	case HardwareChaos:
+		return "Chaos"
+	case HardwareIEEE802:
+		return "IEEE 802 Networks"
+	case HardwareARCNET:
+		return "ARCNET"
+	case HardwareFrameRelay:
+		return "Frame Relay"
+	case HardwareATM:
+		return "ATM"
+	case HardwareHDLC:
+		return "HDLC"
+	case HardwareFibreChannel:
+		return "Fibre Channel"
+	case HardwareSerialLine:
+		return "Serial Line"
+	case HardwareFireWire:
+		return "IEEE 1394 (FireWire)"
+	case HardwareInfiniBand:
+		return "InfiniBand"
+	default:
+		return strconv.Itoa(int(h))
+	}
+}
+
+// String returns the name of an ARP operation, or its numeric value if the
+// operation is not recognized.
+func (o Operation) String() string {
+	switch o {
+	case OperationRequest:
+		return "request"
+	case OperationReply:
+		return "reply"
+	case OperationRARPRequest:
+		return "RARP request"
+	case OperationRARPReply:
+		return "RARP reply"
+	case OperationInARPRequest:
+		return "InARP request"
+	case OperationInARPReply:
+		return "InARP reply"
+	default:
+		return strconv.Itoa(int(o))
+	}
+}